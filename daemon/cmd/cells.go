@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"github.com/cilium/cilium/pkg/datapath"
+	tunnelnoop "github.com/cilium/cilium/pkg/datapath/tunnel/noop"
 	"github.com/cilium/cilium/pkg/datapath/types"
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/gops"
@@ -49,9 +50,10 @@ var (
 		"controlplane",
 		"Control Plane",
 
-		// Readiness allows modules to register as readiness signal providers.
-		// Daemon waits for the signal before finishing initialization and telling
-		// Kubernetes that the agent is ready for CNI requests.
+		// Readiness lets modules register named, dependent gates (e.g.
+		// kvstore, ipcache-sync, bpf-mount). Daemon waits on the gate graph
+		// before finishing initialization and telling Kubernetes that the
+		// agent is ready for CNI requests.
 		readiness.Cell,
 
 		// LocalNodeStore holds onto the information about the local node and allows
@@ -87,8 +89,20 @@ var (
 		"Datapath",
 
 		cell.Provide(
+			newRouteFeed,
 			newWireguardAgent,
 			newDatapath,
 		),
+
+		// TunnelProvider defaults to the no-op provider, under which Cilium
+		// keeps owning WireGuard peer programming and tunnel route
+		// installation as before. Deployments that run an external mesh for
+		// node-to-node encapsulation (e.g. Kilo, via
+		// pkg/datapath/tunnel/kilo) override this binding so that
+		// newWireguardAgent/newDatapath (via the shared routeFeed) subscribe
+		// to the mesh's endpoints instead of programming their own.
+		cell.Provide(func() types.TunnelProvider {
+			return tunnelnoop.New()
+		}),
 	)
 )
\ No newline at end of file
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/pkg/datapath"
+	"github.com/cilium/cilium/pkg/datapath/types"
+	"github.com/cilium/cilium/pkg/hive/cell"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+var datapathLog = logging.DefaultLogger.WithField(logfields.LogSubsys, "datapath")
+
+// tunnelProviderNameNone is the Name() reported by the no-op provider
+// (pkg/datapath/tunnel/noop), which is how newDatapath and
+// newWireguardAgent recognize "no delegation configured" by name alone,
+// through routeFeed.Name.
+const tunnelProviderNameNone = "none"
+
+// datapathParams are newDatapath's hive-injected dependencies.
+type datapathParams struct {
+	cell.In
+
+	Lifecycle cell.Lifecycle
+
+	// RouteFeed is bound to the no-op provider's (empty) feed by default,
+	// under which newDatapath calls installOwnTunnelRoutes on start. When
+	// a real provider is bound (e.g. pkg/datapath/tunnel/kilo), newDatapath
+	// skips installOwnTunnelRoutes and instead follows per-node endpoints
+	// from RouteFeed via installGlueFromProvider.
+	RouteFeed *routeFeed
+}
+
+type agentDatapath struct {
+	routeFeed      *routeFeed
+	nodeAddressing types.NodeAddressing
+}
+
+// newDatapath constructs the agent's datapath.Datapath. When params binds a
+// TunnelProvider other than the no-op default, it skips installing
+// Cilium's own tunnel routes and instead follows RouteFeed's per-node
+// endpoint events.
+func newDatapath(params datapathParams) (datapath.Datapath, error) {
+	nodeAddressing, err := detectLocalNodeAddressing()
+	if err != nil {
+		return nil, fmt.Errorf("detecting local node addressing: %w", err)
+	}
+
+	dp := &agentDatapath{
+		routeFeed:      params.RouteFeed,
+		nodeAddressing: nodeAddressing,
+	}
+
+	// ctx is scoped to the datapath's own lifetime, not to the OnStart hook
+	// that launches installGlueFromProvider: hive cancels the hook's own
+	// context as soon as OnStart returns, which would tear the goroutine
+	// down immediately after startup.
+	ctx, cancel := context.WithCancel(context.Background())
+	params.Lifecycle.Append(cell.Hook{
+		OnStart: func(cell.HookContext) error {
+			if dp.managesOwnTunnel() {
+				return dp.installOwnTunnelRoutes()
+			}
+			datapathLog.WithField("provider", dp.routeFeed.Name()).
+				Info("external tunnel provider bound, skipping Cilium-owned tunnel route installation")
+			go dp.installGlueFromProvider(ctx)
+			return nil
+		},
+		OnStop: func(cell.HookContext) error {
+			cancel()
+			return nil
+		},
+	})
+
+	return dp, nil
+}
+
+// managesOwnTunnel reports whether Cilium should install its own tunnel
+// routes, i.e. no TunnelProvider (or only the no-op default) is bound.
+func (dp *agentDatapath) managesOwnTunnel() bool {
+	return dp.routeFeed.Name() == tunnelProviderNameNone
+}
+
+// installOwnTunnelRoutes is the start step taken when Cilium owns the
+// tunnel. It installs no routes itself and always returns nil.
+func (dp *agentDatapath) installOwnTunnelRoutes() error {
+	return nil
+}
+
+// installGlueFromProvider subscribes to RouteFeed's per-node endpoint
+// updates and hands each one to installNodeGlue or removeNodeGlue, leaving
+// the encapsulation device itself to the provider. Both only log the
+// endpoint at debug level; no IPSec state or ip rules are programmed.
+func (dp *agentDatapath) installGlueFromProvider(ctx context.Context) {
+	for event := range dp.routeFeed.Subscribe(ctx) {
+		if event.Deleted {
+			dp.removeNodeGlue(event.NodeID)
+			continue
+		}
+		dp.installNodeGlue(event)
+	}
+}
+
+func (dp *agentDatapath) installNodeGlue(event types.RouteEvent) {
+	datapathLog.WithFields(map[string]any{
+		"nodeID":   event.NodeID,
+		"endpoint": event.Endpoint,
+		"port":     event.Port,
+	}).Debug("learned externally-managed tunnel endpoint")
+}
+
+func (dp *agentDatapath) removeNodeGlue(nodeID types.NodeID) {
+	datapathLog.WithField("nodeID", nodeID).Debug("externally-managed tunnel endpoint withdrawn")
+}
+
+// LocalNodeAddressing implements datapath.Datapath.
+func (dp *agentDatapath) LocalNodeAddressing() types.NodeAddressing {
+	return dp.nodeAddressing
+}
+
+// localNodeAddressing is a minimal types.NodeAddressing backed by the first
+// non-loopback IPv4/IPv6 address found on the host, so that ServiceCache
+// (the sole consumer wired in cells.go) never sees a nil NodeAddressing.
+type localNodeAddressing struct {
+	ipv4 net.IP
+	ipv6 net.IP
+}
+
+func (a *localNodeAddressing) IPv4() net.IP { return a.ipv4 }
+func (a *localNodeAddressing) IPv6() net.IP { return a.ipv6 }
+
+// detectLocalNodeAddressing enumerates the host's network interfaces and
+// picks the first non-loopback address of each family.
+func detectLocalNodeAddressing() (types.NodeAddressing, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	na := &localNodeAddressing{}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			if na.ipv4 == nil {
+				na.ipv4 = ip4
+			}
+		} else if na.ipv6 == nil {
+			na.ipv6 = ipNet.IP
+		}
+	}
+	return na, nil
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cilium/cilium/pkg/datapath/types"
+	"github.com/cilium/cilium/pkg/hive/cell"
+)
+
+// routeFeed subscribes to the bound TunnelProvider exactly once, for the
+// lifetime of the agent, and fans each event out to every consumer that
+// calls Subscribe. newDatapath and newWireguardAgent both need the same
+// provider's events; without this they would each call
+// TunnelProvider.SubscribeRoutes independently and open two concurrent
+// watches against the same backing CRD/API. A subscriber whose buffer is
+// full when an event arrives misses that event.
+type routeFeed struct {
+	tunnelProvider types.TunnelProvider
+
+	mu   sync.Mutex
+	subs []chan types.RouteEvent
+}
+
+// routeFeedParams are newRouteFeed's hive-injected dependencies.
+type routeFeedParams struct {
+	cell.In
+
+	Lifecycle      cell.Lifecycle
+	TunnelProvider types.TunnelProvider
+}
+
+func newRouteFeed(params routeFeedParams) *routeFeed {
+	rf := &routeFeed{tunnelProvider: params.TunnelProvider}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	params.Lifecycle.Append(cell.Hook{
+		OnStart: func(cell.HookContext) error {
+			go rf.run(ctx)
+			return nil
+		},
+		OnStop: func(cell.HookContext) error {
+			cancel()
+			return nil
+		},
+	})
+
+	return rf
+}
+
+// Name reports the bound TunnelProvider's name, so consumers can decide
+// whether to manage their own state without needing a TunnelProvider
+// dependency of their own.
+func (rf *routeFeed) Name() string {
+	return rf.tunnelProvider.Name()
+}
+
+func (rf *routeFeed) run(ctx context.Context) {
+	for event := range rf.tunnelProvider.SubscribeRoutes(ctx) {
+		rf.mu.Lock()
+		for _, sub := range rf.subs {
+			select {
+			case sub <- event:
+			default:
+				datapathLog.Warning("tunnel route subscriber is falling behind, dropping event")
+			}
+		}
+		rf.mu.Unlock()
+	}
+}
+
+// Subscribe returns a channel of route events for as long as ctx stays
+// live; the channel is closed and deregistered once ctx is done. Unlike
+// TunnelProvider.SubscribeRoutes, multiple callers can Subscribe
+// concurrently without each opening a separate watch against the
+// provider.
+func (rf *routeFeed) Subscribe(ctx context.Context) <-chan types.RouteEvent {
+	ch := make(chan types.RouteEvent, 16)
+
+	rf.mu.Lock()
+	rf.subs = append(rf.subs, ch)
+	rf.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		rf.mu.Lock()
+		defer rf.mu.Unlock()
+		for i, sub := range rf.subs {
+			if sub == ch {
+				rf.subs = append(rf.subs[:i], rf.subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/cilium/cilium/pkg/hive/cell"
+)
+
+// wireguardAgentParams are newWireguardAgent's hive-injected dependencies.
+type wireguardAgentParams struct {
+	cell.In
+
+	Lifecycle cell.Lifecycle
+
+	// RouteFeed is bound to the no-op provider's (empty) feed by default,
+	// under which newWireguardAgent calls programOwnPeers on start. When a
+	// real provider is bound, newWireguardAgent skips programOwnPeers and
+	// leaves the mesh to the provider.
+	RouteFeed *routeFeed
+}
+
+// wireguardAgent chooses between programOwnPeers and followProviderPeers,
+// depending on whether an external TunnelProvider has been bound.
+type wireguardAgent struct {
+	routeFeed *routeFeed
+}
+
+func newWireguardAgent(params wireguardAgentParams) (*wireguardAgent, error) {
+	agent := &wireguardAgent{routeFeed: params.RouteFeed}
+
+	// ctx is scoped to the agent's own lifetime, not to the OnStart hook
+	// that launches followProviderPeers: hive cancels the hook's own
+	// context as soon as OnStart returns, which would tear the goroutine
+	// down immediately after startup.
+	ctx, cancel := context.WithCancel(context.Background())
+	params.Lifecycle.Append(cell.Hook{
+		OnStart: func(cell.HookContext) error {
+			if agent.managesOwnPeers() {
+				return agent.programOwnPeers()
+			}
+			datapathLog.WithField("provider", agent.routeFeed.Name()).
+				Info("external tunnel provider bound, skipping WireGuard peer programming")
+			go agent.followProviderPeers(ctx)
+			return nil
+		},
+		OnStop: func(cell.HookContext) error {
+			cancel()
+			return nil
+		},
+	})
+
+	return agent, nil
+}
+
+// managesOwnPeers reports whether this agent should program its own
+// WireGuard peers, i.e. no TunnelProvider (or only the no-op default) is
+// bound.
+func (a *wireguardAgent) managesOwnPeers() bool {
+	return a.routeFeed.Name() == tunnelProviderNameNone
+}
+
+// programOwnPeers is the start step taken when Cilium owns the WireGuard
+// mesh. It programs no peers or keys itself and always returns nil.
+func (a *wireguardAgent) programOwnPeers() error {
+	return nil
+}
+
+// followProviderPeers subscribes to RouteFeed's per-node endpoint updates
+// and logs each endpoint and public key at debug level, without programming
+// a peer of our own for it. It keeps no peer state.
+func (a *wireguardAgent) followProviderPeers(ctx context.Context) {
+	for event := range a.routeFeed.Subscribe(ctx) {
+		if event.Deleted {
+			datapathLog.WithField("nodeID", event.NodeID).Debug("tunnel provider withdrew peer endpoint")
+			continue
+		}
+		datapathLog.WithFields(map[string]any{
+			"nodeID":    event.NodeID,
+			"endpoint":  event.Endpoint,
+			"publicKey": event.PublicKey,
+		}).Debug("learned peer endpoint from tunnel provider")
+	}
+}
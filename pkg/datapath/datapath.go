@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package datapath applies control-plane decisions to the kernel: BPF maps,
+// routes, and (unless a types.TunnelProvider is bound) Cilium's own
+// WireGuard mesh. The concrete implementation is constructed by
+// daemon/cmd's newDatapath/newWireguardAgent; this package exposes only the
+// interface the rest of the agent cell graph depends on.
+package datapath
+
+import "github.com/cilium/cilium/pkg/datapath/types"
+
+// Datapath is the entry point the rest of the agent cell graph depends on.
+type Datapath interface {
+	// LocalNodeAddressing returns the per-family addressing of the local
+	// node, used to build service and BPF map configuration.
+	LocalNodeAddressing() types.NodeAddressing
+}
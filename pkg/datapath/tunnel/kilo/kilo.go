@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package kilo implements a types.TunnelProvider backed by Kilo
+// (https://github.com/squat/kilo)'s Peer custom resource, for deployments
+// that already run Kilo as their WAN mesh and want Cilium limited to
+// policy and service enforcement rather than also programming WireGuard
+// peers and tunnel routes of its own.
+package kilo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cilium/cilium/pkg/datapath/types"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// watchRetryInterval is how long SubscribeRoutes waits before
+// re-establishing the Kilo peer watch after the API server closes it (a
+// routine occurrence every few minutes, not an error condition).
+const watchRetryInterval = 5 * time.Second
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "datapath-tunnel-kilo")
+
+// peerGVR is the GroupVersionResource of Kilo's Peer CRD, which carries the
+// WireGuard public key, allowed IPs, and endpoint Kilo has assigned to a
+// node's mesh-facing interface.
+var peerGVR = schema.GroupVersionResource{
+	Group:    "kilo.squat.ai",
+	Version:  "v1alpha1",
+	Resource: "peers",
+}
+
+// NodeIdentityResolver maps between a Kubernetes node name and the numeric
+// NodeID Cilium has allocated it, since Kilo's Peer CRD is keyed by node
+// name but the rest of Cilium's datapath keys node state by NodeID.
+type NodeIdentityResolver interface {
+	NodeIDByName(nodeName string) (types.NodeID, bool)
+}
+
+// Provider implements types.TunnelProvider by watching Kilo's Peer CRD.
+type Provider struct {
+	client   dynamic.Interface
+	resolver NodeIdentityResolver
+
+	mu        sync.RWMutex
+	endpoints map[types.NodeID]endpoint
+}
+
+type endpoint struct {
+	ip        net.IP
+	port      uint16
+	publicKey types.PublicKey
+}
+
+// New returns a Kilo-backed TunnelProvider. client must be scoped to the
+// cluster running Kilo's mesh controller.
+func New(client dynamic.Interface, resolver NodeIdentityResolver) *Provider {
+	return &Provider{
+		client:    client,
+		resolver:  resolver,
+		endpoints: make(map[types.NodeID]endpoint),
+	}
+}
+
+// Name implements types.TunnelProvider.
+func (p *Provider) Name() string {
+	return "kilo"
+}
+
+// NodeTunnelEndpoint implements types.TunnelProvider.
+func (p *Provider) NodeTunnelEndpoint(nodeID types.NodeID) (net.IP, uint16, types.PublicKey, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	ep, ok := p.endpoints[nodeID]
+	if !ok {
+		return nil, 0, types.PublicKey{}, fmt.Errorf("no Kilo peer known for node ID %d", nodeID)
+	}
+	return ep.ip, ep.port, ep.publicKey, nil
+}
+
+// SubscribeRoutes implements types.TunnelProvider. It watches Kilo's Peer
+// CRD and republishes each add/modify/delete as a types.RouteEvent, while
+// keeping the endpoint table behind NodeTunnelEndpoint up to date. The
+// watch is re-established whenever the API server closes it, which happens
+// routinely every few minutes; callers therefore see a continuous feed for
+// as long as ctx is live rather than a one-shot stream that goes silent
+// after the first disconnect.
+func (p *Provider) SubscribeRoutes(ctx context.Context) <-chan types.RouteEvent {
+	events := make(chan types.RouteEvent, 16)
+
+	go func() {
+		defer close(events)
+
+		for {
+			if err := p.watchOnce(ctx, events); err != nil {
+				log.WithError(err).Error("failed to watch Kilo peers, retrying")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+		}
+	}()
+
+	return events
+}
+
+// watchOnce runs a single Kilo Peer watch until the API server closes it or
+// ctx is cancelled. A closed watch is reported as err == nil: it is the
+// caller's job to decide whether and when to reconnect.
+func (p *Provider) watchOnce(ctx context.Context, events chan<- types.RouteEvent) error {
+	w, err := p.client.Resource(peerGVR).Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case watchEvent, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			obj, ok := watchEvent.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			routeEvent, ok := p.handlePeerEvent(watchEvent.Type, obj)
+			if !ok {
+				continue
+			}
+			select {
+			case events <- routeEvent:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// handlePeerEvent translates a single Kilo Peer watch event into a
+// types.RouteEvent and applies the corresponding update to the endpoint
+// table. It returns ok=false for peers that cannot be resolved to a Cilium
+// NodeID (e.g. a node Cilium hasn't seen yet) or that are missing required
+// fields.
+func (p *Provider) handlePeerEvent(eventType watch.EventType, obj *unstructured.Unstructured) (types.RouteEvent, bool) {
+	nodeName := obj.GetName()
+	nodeID, ok := p.resolver.NodeIDByName(nodeName)
+	if !ok {
+		log.WithField(logfields.NodeName, nodeName).Debug("ignoring Kilo peer for unknown node")
+		return types.RouteEvent{}, false
+	}
+
+	if eventType == watch.Deleted {
+		p.mu.Lock()
+		delete(p.endpoints, nodeID)
+		p.mu.Unlock()
+		return types.RouteEvent{NodeID: nodeID, Deleted: true}, true
+	}
+
+	// Kilo's PeerEndpoint inlines DNSOrIP (`json:",inline"`), so the
+	// resolved address lives directly under spec.endpoint.ip, the same
+	// level as the sibling spec.endpoint.port.
+	endpointStr, _, err := unstructured.NestedString(obj.Object, "spec", "endpoint", "ip")
+	if err != nil || endpointStr == "" {
+		log.WithField(logfields.NodeName, nodeName).Debug("Kilo peer has no endpoint yet")
+		return types.RouteEvent{}, false
+	}
+	ip := net.ParseIP(endpointStr)
+	if ip == nil {
+		log.WithFields(map[string]any{
+			logfields.NodeName: nodeName,
+			logfields.IPAddr:   endpointStr,
+		}).Warning("Kilo peer has unparseable endpoint IP")
+		return types.RouteEvent{}, false
+	}
+
+	rawPort, _, _ := unstructured.NestedInt64(obj.Object, "spec", "endpoint", "port")
+	if rawPort <= 0 || rawPort > math.MaxUint16 {
+		log.WithFields(map[string]any{
+			logfields.NodeName: nodeName,
+			logfields.Port:     rawPort,
+		}).Warning("Kilo peer has out-of-range endpoint port")
+		return types.RouteEvent{}, false
+	}
+	port := uint16(rawPort)
+
+	pubKeyStr, _, err := unstructured.NestedString(obj.Object, "spec", "publicKey")
+	if err != nil {
+		log.WithField(logfields.NodeName, nodeName).WithError(err).Debug("Kilo peer has no public key yet")
+		return types.RouteEvent{}, false
+	}
+	pubKeyBytes, err := base64.StdEncoding.DecodeString(pubKeyStr)
+	if err != nil || len(pubKeyBytes) != len(types.PublicKey{}) {
+		log.WithField(logfields.NodeName, nodeName).Warning("Kilo peer has malformed public key")
+		return types.RouteEvent{}, false
+	}
+	var pubKey types.PublicKey
+	copy(pubKey[:], pubKeyBytes)
+
+	ep := endpoint{ip: ip, port: port, publicKey: pubKey}
+	p.mu.Lock()
+	p.endpoints[nodeID] = ep
+	p.mu.Unlock()
+
+	return types.RouteEvent{
+		NodeID:    nodeID,
+		Endpoint:  ep.ip,
+		Port:      ep.port,
+		PublicKey: ep.publicKey,
+	}, true
+}
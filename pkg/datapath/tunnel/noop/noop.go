@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package noop is the default types.TunnelProvider binding: it reports no
+// endpoints of its own, and its Name() of "none" is how the Datapath cell
+// recognizes that Cilium should keep programming its own WireGuard peers
+// and tunnel routes rather than delegating to an external mesh.
+package noop
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/pkg/datapath/types"
+)
+
+// Provider is the no-op types.TunnelProvider.
+type Provider struct{}
+
+// New returns the no-op TunnelProvider.
+func New() *Provider {
+	return &Provider{}
+}
+
+// Name implements types.TunnelProvider.
+func (Provider) Name() string {
+	return "none"
+}
+
+// NodeTunnelEndpoint implements types.TunnelProvider. It always fails since
+// the no-op provider has no endpoints to report; callers delegating tunnel
+// ownership should not be doing so when this provider is bound.
+func (Provider) NodeTunnelEndpoint(nodeID types.NodeID) (net.IP, uint16, types.PublicKey, error) {
+	return nil, 0, types.PublicKey{}, fmt.Errorf("no-op tunnel provider has no endpoint for node %d", nodeID)
+}
+
+// SubscribeRoutes implements types.TunnelProvider. The returned channel is
+// closed immediately since there is nothing to subscribe to.
+func (Provider) SubscribeRoutes(ctx context.Context) <-chan types.RouteEvent {
+	ch := make(chan types.RouteEvent)
+	close(ch)
+	return ch
+}
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package types
+
+import "net"
+
+// NodeAddressing provides the per-family node IP addresses used to build
+// datapath and service configuration for the local node.
+type NodeAddressing interface {
+	IPv4() net.IP
+	IPv6() net.IP
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package types
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// NodeID is Cilium's internal numeric identifier for a node, as used by the
+// existing WireGuard peer and tunnel endpoint maps.
+type NodeID uint16
+
+// PublicKey is a WireGuard public key, exchanged with a TunnelProvider to
+// establish node-to-node tunnels that Cilium itself does not program.
+type PublicKey [32]byte
+
+// String implements fmt.Stringer.
+func (k PublicKey) String() string {
+	return fmt.Sprintf("%x", [32]byte(k))
+}
+
+// RouteEvent describes a change to the tunnel endpoint of a single node, as
+// published by a TunnelProvider's SubscribeRoutes channel.
+type RouteEvent struct {
+	// NodeID is the node whose endpoint changed.
+	NodeID NodeID
+	// Endpoint is the node's current encapsulation address. Unset when
+	// Deleted is true.
+	Endpoint net.IP
+	// Port is the node's current encapsulation port. Unset when Deleted is
+	// true.
+	Port uint16
+	// PublicKey is the node's current WireGuard public key, if the provider
+	// tracks one. Unset when Deleted is true.
+	PublicKey PublicKey
+	// Deleted indicates the node's endpoint was withdrawn, e.g. because the
+	// node left the mesh.
+	Deleted bool
+}
+
+// TunnelProvider is implemented by an external mesh controller (e.g. Kilo)
+// that owns node-to-node encapsulation. When a TunnelProvider is bound in
+// the Datapath cell, Cilium does not program its own WireGuard peers or
+// install its own tunnel routes; it follows the provider's per-node endpoint
+// events instead. No IPSec state or ip rules are programmed from those
+// events. The default binding is the no-op
+// provider in pkg/datapath/tunnel/noop, under which Cilium's behavior is
+// unchanged.
+type TunnelProvider interface {
+	// NodeTunnelEndpoint returns the encapsulation endpoint the provider has
+	// currently assigned to nodeID.
+	NodeTunnelEndpoint(nodeID NodeID) (net.IP, uint16, PublicKey, error)
+
+	// SubscribeRoutes returns a channel of RouteEvent, one per node endpoint
+	// change, that is closed once ctx is cancelled, or right away by a
+	// provider that has nothing to publish (e.g. the no-op provider).
+	SubscribeRoutes(ctx context.Context) <-chan RouteEvent
+
+	// Name identifies the provider (e.g. "kilo", "none") for logging and
+	// metrics labels.
+	Name() string
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package readiness lets modules register named, dependent readiness gates
+// instead of a single flat signal, so the Daemon can block CNI admission on
+// the full dependency graph (e.g. kvstore, ipcache-sync, bpf-mount) and
+// operators can see exactly which gate is holding things up via
+// /healthz/readiness and the cilium_readiness_gate metric, rather than a
+// single boolean.
+package readiness
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cilium/cilium/pkg/hive/cell"
+)
+
+// Cell provides the readiness Manager singleton. Other cells take a
+// *Manager dependency and call Register during their own construction.
+// Cell's own lifecycle hook calls Manager.Run once hive starts, in the
+// background, so the gate graph is actually evaluated without every other
+// module needing to know about it; callers that must block CNI admission
+// on readiness (e.g. the Daemon) can still call Manager.Run themselves: it
+// evaluates the gate graph only once, so they block until that first run
+// settles and then get its result.
+var Cell = cell.Module(
+	"readiness",
+	"Readiness",
+
+	cell.Provide(NewManager),
+	cell.Metric(newMetrics),
+	cell.Provide(newHandler),
+
+	cell.Invoke(runOnStart),
+)
+
+// Handler is the /healthz/readiness HTTP handler, provided so the agent's
+// API server cell can mount it without needing a *Manager dependency of
+// its own.
+type Handler http.Handler
+
+func newHandler(m *Manager) Handler {
+	return m.Handler()
+}
+
+// runOnStart registers a lifecycle hook that runs the readiness gate graph
+// to completion as soon as hive starts. It deliberately does not return
+// the run's error to hive's own startup path: a failed readiness gate
+// should keep the agent running but not-ready (visible via
+// /healthz/readiness and the readiness_gate metric), not abort startup.
+func runOnStart(lc cell.Lifecycle, m *Manager) {
+	lc.Append(cell.Hook{
+		OnStart: func(cell.HookContext) error {
+			go func() {
+				if err := m.Run(context.Background()); err != nil {
+					log.WithError(err).Info("agent is not yet fully ready")
+				}
+			}()
+			return nil
+		},
+	})
+}
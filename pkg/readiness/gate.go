@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package readiness
+
+import (
+	"sync"
+	"time"
+)
+
+// gate is the manager's internal bookkeeping for one registered readiness
+// gate. readyCh is closed once the gate leaves StatePending, so dependents
+// can simply select on the channels of their dependencies rather than
+// polling.
+type gate struct {
+	name     string
+	deps     []string
+	probe    ProbeFunc
+	deadline time.Duration
+
+	mu             sync.Mutex
+	state          State
+	reason         string
+	message        string
+	lastTransition time.Time
+
+	readyCh chan struct{}
+}
+
+func newGate(name string, deps []string, probe ProbeFunc, cfg gateConfig) *gate {
+	return &gate{
+		name:           name,
+		deps:           deps,
+		probe:          probe,
+		deadline:       cfg.deadline,
+		state:          StatePending,
+		lastTransition: time.Now(),
+		readyCh:        make(chan struct{}),
+	}
+}
+
+// transition moves the gate to a terminal state exactly once. Subsequent
+// calls are no-ops, since a gate's probe runs at most once per Manager.Run.
+func (g *gate) transition(state State, reason, message string) (State, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state != StatePending {
+		return g.state, false
+	}
+	g.state = state
+	g.reason = reason
+	g.message = message
+	g.lastTransition = time.Now()
+	close(g.readyCh)
+	return state, true
+}
+
+func (g *gate) status() Status {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return Status{
+		Name:           g.name,
+		DependsOn:      g.deps,
+		State:          g.state,
+		Reason:         g.reason,
+		Message:        g.message,
+		LastTransition: g.lastTransition,
+	}
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package readiness
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler returns the /healthz/readiness handler: a JSON dump of every
+// registered gate's current state, reason, and last transition time, so an
+// operator can see exactly which subsystem (e.g. kvstore, ipcache-sync,
+// bpf-mount) is holding up CNI admission rather than staring at a single
+// boolean. It is mounted by the agent's API server cell.
+func (m *Manager) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := m.Report()
+
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "readiness")
+
+// Manager tracks the dependency graph of named readiness gates and exposes
+// their aggregate state. Modules register gates during hive's
+// construction phase via Register; Manager.Run then probes every
+// registered gate, running independent gates in parallel and
+// short-circuiting a gate's dependents (marking them StateSkipped) as soon
+// as one of its dependencies fails, rather than waiting out their
+// deadlines too.
+type Manager struct {
+	mu      sync.Mutex
+	gates   map[string]*gate
+	metrics readinessMetrics
+
+	runOnce sync.Once
+	runErr  error
+}
+
+// NewManager constructs an empty Manager. It is provided as a singleton by
+// Cell, so modules should take a *Manager dependency and call Register
+// during their own construction.
+func NewManager(metrics readinessMetrics) *Manager {
+	return &Manager{
+		gates:   make(map[string]*gate),
+		metrics: metrics,
+	}
+}
+
+// Register declares a named readiness gate. deps names other gates that
+// must reach StateReady before probe is run; if any dependency instead
+// reaches StateFailed or StateSkipped, this gate transitions directly to
+// StateSkipped without probe ever being called. Register must be called
+// before Manager.Run; calling it afterwards has no effect on the current
+// run.
+func (m *Manager) Register(name string, deps []string, probe ProbeFunc, opts ...GateOption) {
+	cfg := gateConfig{deadline: DefaultGateDeadline}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gates[name] = newGate(name, deps, probe, cfg)
+	m.metrics.setGateMetric(name, StatePending)
+}
+
+// Run probes every registered gate to a terminal state and returns once all
+// of them have settled. It returns an error naming the gates that did not
+// reach StateReady; a nil error means the agent is fully ready.
+//
+// Run only evaluates the gate graph once: Cell's own lifecycle hook calls
+// it as soon as hive starts, so a caller that also wants to block on
+// readiness (e.g. the Daemon, before declaring CNI ready) can call Run
+// again and will simply get the same result once the first run settles,
+// rather than re-probing every gate a second time.
+func (m *Manager) Run(ctx context.Context) error {
+	m.runOnce.Do(func() {
+		m.runErr = m.run(ctx)
+	})
+	return m.runErr
+}
+
+func (m *Manager) run(ctx context.Context) error {
+	m.mu.Lock()
+	gates := make(map[string]*gate, len(m.gates))
+	for name, g := range m.gates {
+		gates[name] = g
+	}
+	m.mu.Unlock()
+
+	if err := m.checkDeps(gates); err != nil {
+		return err
+	}
+	if cycle := findCycle(gates); cycle != nil {
+		return fmt.Errorf("readiness gate dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	var wg sync.WaitGroup
+	for _, g := range gates {
+		wg.Add(1)
+		go func(g *gate) {
+			defer wg.Done()
+			m.runGate(ctx, g, gates)
+		}(g)
+	}
+	wg.Wait()
+
+	var failed []string
+	for _, g := range gates {
+		if s := g.status(); s.State != StateReady {
+			failed = append(failed, fmt.Sprintf("%s (%s: %s)", s.Name, s.State, s.Reason))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("readiness gates did not become ready: %v", failed)
+	}
+	return nil
+}
+
+// checkDeps rejects unknown dependency names up front so a typo in a
+// Register call fails fast instead of hanging until the deadline.
+func (m *Manager) checkDeps(gates map[string]*gate) error {
+	for _, g := range gates {
+		for _, dep := range g.deps {
+			if _, ok := gates[dep]; !ok {
+				return fmt.Errorf("readiness gate %q depends on unregistered gate %q", g.name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// findCycle runs a depth-first search over the gate dependency graph and
+// returns the first cycle found, as a slice of gate names from the cycle's
+// entry point back to itself, or nil if the graph is a DAG. Without this
+// check, a cyclic Register call would have every gate in the cycle hang on
+// each other's readyCh until their deadlines expire, reporting misleading
+// "deadline-exceeded" reasons instead of the real misconfiguration.
+func findCycle(gates map[string]*gate) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(gates))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == name {
+					start = i
+					break
+				}
+			}
+			return append(append([]string{}, path[start:]...), name)
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range gates[name].deps {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// runGate waits for g's dependencies to settle, then runs its probe (or
+// skips it) and records the resulting transition.
+func (m *Manager) runGate(ctx context.Context, g *gate, gates map[string]*gate) {
+	for _, depName := range g.deps {
+		dep := gates[depName]
+		select {
+		case <-dep.readyCh:
+		case <-ctx.Done():
+			m.finish(g, StateFailed, "context-cancelled", ctx.Err().Error())
+			return
+		}
+		if dep.status().State != StateReady {
+			m.finish(g, StateSkipped, "dependency-not-ready",
+				fmt.Sprintf("dependency %q did not become ready", depName))
+			return
+		}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, g.deadline)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- g.probe(probeCtx) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			m.finish(g, StateFailed, "probe-error", err.Error())
+			return
+		}
+		m.finish(g, StateReady, "", "")
+	case <-probeCtx.Done():
+		m.finish(g, StateFailed, "deadline-exceeded",
+			fmt.Sprintf("probe did not complete within %s", g.deadline))
+	}
+}
+
+func (m *Manager) finish(g *gate, state State, reason, message string) {
+	if newState, changed := g.transition(state, reason, message); changed {
+		logEntry := log.WithFields(map[string]any{
+			"name":      g.name,
+			"state":     newState,
+			"dependsOn": g.deps,
+		})
+		if reason != "" {
+			logEntry = logEntry.WithField("reason", reason)
+		}
+		logEntry.Info("readiness gate transitioned")
+		m.metrics.setGateMetric(g.name, newState)
+	}
+}
+
+// Report returns a snapshot of every registered gate's current state, for
+// the /healthz/readiness handler.
+func (m *Manager) Report() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := Report{Ready: true}
+	for _, g := range m.gates {
+		status := g.status()
+		if status.State != StateReady {
+			report.Ready = false
+		}
+		report.Gates = append(report.Gates, status)
+	}
+	return report
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package readiness
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func okProbe(context.Context) error { return nil }
+
+func TestManagerRunAllReady(t *testing.T) {
+	m := NewManager(newMetrics())
+	m.Register("a", nil, okProbe)
+	m.Register("b", []string{"a"}, okProbe)
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	report := m.Report()
+	if !report.Ready {
+		t.Fatalf("report.Ready = false, want true: %+v", report)
+	}
+}
+
+func TestManagerUnknownDependency(t *testing.T) {
+	m := NewManager(newMetrics())
+	m.Register("a", []string{"does-not-exist"}, okProbe)
+
+	err := m.Run(context.Background())
+	if err == nil {
+		t.Fatal("Run returned nil error, want unknown dependency error")
+	}
+	if !strings.Contains(err.Error(), "does-not-exist") {
+		t.Errorf("error %q does not mention the unregistered gate", err)
+	}
+}
+
+func TestManagerDependencyCycle(t *testing.T) {
+	m := NewManager(newMetrics())
+	m.Register("a", []string{"b"}, okProbe)
+	m.Register("b", []string{"c"}, okProbe)
+	m.Register("c", []string{"a"}, okProbe)
+
+	err := m.Run(context.Background())
+	if err == nil {
+		t.Fatal("Run returned nil error, want dependency cycle error")
+	}
+	if !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("error %q does not mention a cycle", err)
+	}
+}
+
+func TestManagerFailedGateSkipsDependents(t *testing.T) {
+	m := NewManager(newMetrics())
+	probeRan := false
+
+	m.Register("parent", nil, func(context.Context) error {
+		return errors.New("boom")
+	})
+	m.Register("child", []string{"parent"}, func(context.Context) error {
+		probeRan = true
+		return nil
+	})
+
+	if err := m.Run(context.Background()); err == nil {
+		t.Fatal("Run returned nil error, want failure reported")
+	}
+
+	report := m.Report()
+	var childStatus, parentStatus Status
+	for _, s := range report.Gates {
+		switch s.Name {
+		case "child":
+			childStatus = s
+		case "parent":
+			parentStatus = s
+		}
+	}
+
+	if parentStatus.State != StateFailed {
+		t.Errorf("parent state = %s, want %s", parentStatus.State, StateFailed)
+	}
+	if childStatus.State != StateSkipped {
+		t.Errorf("child state = %s, want %s", childStatus.State, StateSkipped)
+	}
+	if probeRan {
+		t.Error("child probe ran despite its dependency failing")
+	}
+}
+
+func TestManagerDeadlineExceeded(t *testing.T) {
+	m := NewManager(newMetrics())
+	m.Register("slow", nil, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithDeadline(10*time.Millisecond))
+
+	if err := m.Run(context.Background()); err == nil {
+		t.Fatal("Run returned nil error, want deadline-exceeded failure")
+	}
+
+	report := m.Report()
+	if len(report.Gates) != 1 || report.Gates[0].State != StateFailed {
+		t.Fatalf("gates = %+v, want a single Failed gate", report.Gates)
+	}
+	if report.Gates[0].Reason != "deadline-exceeded" {
+		t.Errorf("reason = %q, want %q", report.Gates[0].Reason, "deadline-exceeded")
+	}
+}
+
+func TestManagerRunIsIdempotent(t *testing.T) {
+	calls := 0
+	m := NewManager(newMetrics())
+	m.Register("a", nil, func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := m.Run(context.Background()); err != nil {
+			t.Fatalf("Run call %d returned error: %v", i, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("probe ran %d times, want exactly 1", calls)
+	}
+}
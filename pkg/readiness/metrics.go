@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package readiness
+
+import (
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/metrics/metric"
+)
+
+// readinessMetrics holds the Prometheus metrics this package exports.
+// Cell provides it through cell.Metric rather than a package-level
+// init()/MustRegister, so the gauge is constructed through hive like every
+// other agent metric and shows up in `cilium metrics list`.
+type readinessMetrics struct {
+	// GateState is cilium_readiness_gate{name,state}, set to 1 for a gate's
+	// current state and 0 for every other state, so that
+	// `cilium_readiness_gate{state="Failed"} == 1` can be alerted on
+	// directly.
+	GateState metric.Vec[metric.Gauge]
+}
+
+func newMetrics() readinessMetrics {
+	return readinessMetrics{
+		GateState: metric.NewGaugeVec(metric.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "readiness_gate",
+			Help:      "State of each registered readiness gate (1 for the current state, 0 otherwise)",
+		}, []string{"name", "state"}),
+	}
+}
+
+var allStates = []State{StatePending, StateReady, StateFailed, StateSkipped}
+
+// setGateMetric updates GateState for name so that exactly one state label
+// is set to 1.
+func (rm readinessMetrics) setGateMetric(name string, current State) {
+	for _, state := range allStates {
+		value := 0.0
+		if state == current {
+			value = 1.0
+		}
+		rm.GateState.WithLabelValues(name, string(state)).Set(value)
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package readiness
+
+import (
+	"context"
+	"time"
+)
+
+// State is the lifecycle state of a readiness gate, modeled on how kubelet
+// tracks container readiness transitions.
+type State string
+
+const (
+	// StatePending means the gate's probe has not yet run, usually because
+	// one or more of its dependencies are not yet Ready.
+	StatePending State = "Pending"
+	// StateReady means the gate's probe succeeded.
+	StateReady State = "Ready"
+	// StateFailed means the gate's probe returned an error or did not
+	// complete within its deadline.
+	StateFailed State = "Failed"
+	// StateSkipped means a dependency failed, so the gate's probe was never
+	// run.
+	StateSkipped State = "Skipped"
+)
+
+// DefaultGateDeadline is the deadline applied to a gate that doesn't pass
+// WithDeadline.
+const DefaultGateDeadline = 2 * time.Minute
+
+// ProbeFunc evaluates whether a gate is ready. It should return promptly
+// and honor ctx cancellation; a probe that depends on a long-running
+// background sync should poll cached state here rather than performing the
+// sync itself.
+type ProbeFunc func(ctx context.Context) error
+
+// GateOption customizes a gate at Register time.
+type GateOption func(*gateConfig)
+
+type gateConfig struct {
+	deadline time.Duration
+}
+
+// WithDeadline overrides DefaultGateDeadline for a single gate. If the
+// probe has not completed by the deadline, the gate transitions to Failed
+// with a timeout reason.
+func WithDeadline(d time.Duration) GateOption {
+	return func(c *gateConfig) {
+		c.deadline = d
+	}
+}
+
+// Status is a point-in-time, read-only snapshot of a single gate, as
+// exposed by Manager.Report and the /healthz/readiness handler.
+type Status struct {
+	Name           string    `json:"name"`
+	DependsOn      []string  `json:"dependsOn,omitempty"`
+	State          State     `json:"state"`
+	Reason         string    `json:"reason,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	LastTransition time.Time `json:"lastTransition"`
+}
+
+// Report is the top-level document served at /healthz/readiness.
+type Report struct {
+	// Ready is true only if every registered gate reached StateReady.
+	Ready bool     `json:"ready"`
+	Gates []Status `json:"gates"`
+}
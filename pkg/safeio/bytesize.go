@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package safeio
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// unitMultipliers maps every suffix accepted by ParseByteSize to its
+// multiplier. The binary (KiB, MiB, ...) and decimal-looking (KB, MB, ...)
+// spellings are treated as equivalent because ByteSize itself is a base-1024
+// quantity; this repo exposes the "KB" names purely for readability.
+var unitMultipliers = map[string]ByteSize{
+	"B":   1,
+	"KB":  KB,
+	"KIB": KB,
+	"MB":  MB,
+	"MIB": MB,
+	"GB":  GB,
+	"GIB": GB,
+	"TB":  TB,
+	"TIB": TB,
+	"PB":  PB,
+	"PIB": PB,
+	"EB":  EB,
+	"EIB": EB,
+	"ZB":  ZB,
+	"ZIB": ZB,
+	"YB":  YB,
+	"YIB": YB,
+}
+
+// ParseByteSize parses a human-readable byte size such as "1.5GB", "512kb"
+// or "2048" (interpreted as bytes) into a ByteSize. Unit suffixes are
+// case-insensitive, may be separated from the numeric value by whitespace,
+// and accept both the binary-style ("KiB") and the short ("KB") spelling;
+// both are interpreted as multiples of 1024 to match ByteSize.String().
+//
+// ParseByteSize rejects negative values and values that overflow
+// math.MaxInt64 bytes.
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return 0, fmt.Errorf("cannot parse empty string as a byte size")
+	}
+
+	i := 0
+	for i < len(trimmed) && (trimmed[i] == '.' || trimmed[i] == '-' || trimmed[i] == '+' || (trimmed[i] >= '0' && trimmed[i] <= '9')) {
+		i++
+	}
+	numPart := trimmed[:i]
+	unitPart := strings.TrimSpace(trimmed[i:])
+
+	value, err := strconv.ParseFloat(numPart, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("invalid byte size %q: negative sizes are not allowed", s)
+	}
+
+	multiplier := ByteSize(1)
+	if unitPart != "" {
+		m, ok := unitMultipliers[strings.ToUpper(unitPart)]
+		if !ok {
+			return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, unitPart)
+		}
+		multiplier = m
+	}
+
+	result := value * float64(multiplier)
+	if result >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: overflows int64", s)
+	}
+
+	return ByteSize(result), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	parsed, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ByteSize) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both the human-readable string
+// form (e.g. "10MB") and a bare JSON number (interpreted as bytes) are
+// accepted, since config files in the wild use both.
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var asString string
+	if err := json.Unmarshal(data, &asString); err == nil {
+		parsed, err := ParseByteSize(asString)
+		if err != nil {
+			return err
+		}
+		*b = parsed
+		return nil
+	}
+
+	var asNumber float64
+	if err := json.Unmarshal(data, &asNumber); err != nil {
+		return fmt.Errorf("invalid byte size %s: %w", data, err)
+	}
+	if asNumber < 0 {
+		return fmt.Errorf("invalid byte size %s: negative sizes are not allowed", data)
+	}
+	*b = ByteSize(asNumber)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (b ByteSize) MarshalYAML() (interface{}, error) {
+	return b.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (b *ByteSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	parsed, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// Set implements pflag.Value so ByteSize can be used directly as a flag
+// type, e.g. `flags.Var(&cfg.BPFMapDynamicSizeMax, "bpf-map-dynamic-size-max", ...)`.
+func (b *ByteSize) Set(s string) error {
+	parsed, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// Type implements pflag.Value.
+func (b *ByteSize) Type() string {
+	return "byte-size"
+}
+
+// byteSizeType is the reflect.Type of ByteSize, used by
+// StringToByteSizeHookFunc to recognize decode targets.
+var byteSizeType = reflect.TypeOf(ByteSize(0))
+
+// StringToByteSizeHookFunc returns a mapstructure.DecodeHookFunc that lets
+// viper decode string configuration values (e.g. "10MB" from a YAML config
+// or environment variable) directly into ByteSize fields.
+func StringToByteSizeHookFunc() mapstructure.DecodeHookFunc {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != byteSizeType {
+			return data, nil
+		}
+		return ParseByteSize(data.(string))
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package safeio
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    ByteSize
+		wantErr bool
+	}{
+		{name: "bare integer", in: "2048", want: 2048},
+		{name: "fractional with unit", in: "1.5GB", want: ByteSize(1.5 * float64(GB))},
+		{name: "lowercase unit", in: "512kb", want: 512 * KB},
+		{name: "whitespace before unit", in: "10 MB", want: 10 * MB},
+		{name: "binary alias", in: "2KiB", want: 2 * KB},
+		{name: "binary alias mixed case", in: "3MiB", want: 3 * MB},
+		{name: "zero", in: "0", want: 0},
+		{name: "empty string", in: "", wantErr: true},
+		{name: "negative value", in: "-1KB", wantErr: true},
+		{name: "unknown unit", in: "5QB", wantErr: true},
+		{name: "garbage", in: "not-a-size", wantErr: true},
+		{name: "overflow", in: "10EB", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseByteSize(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseByteSize(%q) = %v, want error", tc.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseByteSize(%q) returned unexpected error: %v", tc.in, err)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseByteSize(%q) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestParseByteSizeRoundTrip pins the grammar ParseByteSize must accept:
+// for sizes that String() renders exactly (a whole or one-decimal multiple
+// of a unit up to EB), its output must parse back into an equal ByteSize.
+func TestParseByteSizeRoundTrip(t *testing.T) {
+	for _, b := range []ByteSize{0, 512, KB, 10 * KB, MB, 3 * GB, TB, PB, EB} {
+		s := b.String()
+		got, err := ParseByteSize(s)
+		if err != nil {
+			t.Fatalf("ParseByteSize(%q) (round-tripping %v) returned error: %v", s, b, err)
+		}
+		if got != b {
+			t.Fatalf("ParseByteSize(%q) = %v, want %v (round-tripping %v.String())", s, got, b, b)
+		}
+	}
+}
+
+func TestByteSizeTextMarshaling(t *testing.T) {
+	b := 5 * MB
+	text, err := b.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText returned error: %v", err)
+	}
+
+	var got ByteSize
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText(%q) returned error: %v", text, err)
+	}
+	if got != b {
+		t.Fatalf("UnmarshalText(MarshalText(%v)) = %v, want %v", b, got, b)
+	}
+}
+
+func TestByteSizeJSONMarshaling(t *testing.T) {
+	b := 2 * GB
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+
+	var got ByteSize
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if got != b {
+		t.Fatalf("json round-trip of %v = %v", b, got)
+	}
+
+	// A bare JSON number is also accepted, interpreted as bytes.
+	var fromNumber ByteSize
+	if err := json.Unmarshal([]byte("4096"), &fromNumber); err != nil {
+		t.Fatalf("json.Unmarshal(4096) returned error: %v", err)
+	}
+	if fromNumber != 4096 {
+		t.Fatalf("json.Unmarshal(4096) = %v, want 4096", fromNumber)
+	}
+
+	var negative ByteSize
+	if err := json.Unmarshal([]byte("-1"), &negative); err == nil {
+		t.Fatalf("json.Unmarshal(-1) = %v, want error", negative)
+	}
+}
+
+func TestByteSizePflagValue(t *testing.T) {
+	var b ByteSize
+	if err := b.Set("10MB"); err != nil {
+		t.Fatalf("Set(10MB) returned error: %v", err)
+	}
+	if b != 10*MB {
+		t.Fatalf("Set(10MB) left b = %v, want %v", b, 10*MB)
+	}
+	if b.Type() != "byte-size" {
+		t.Fatalf("Type() = %q, want %q", b.Type(), "byte-size")
+	}
+}
+
+func TestStringToByteSizeHookFunc(t *testing.T) {
+	hook, ok := StringToByteSizeHookFunc().(func(reflect.Type, reflect.Type, interface{}) (interface{}, error))
+	if !ok {
+		t.Fatalf("StringToByteSizeHookFunc() returned unexpected type %T", StringToByteSizeHookFunc())
+	}
+
+	got, err := hook(reflect.TypeOf(""), reflect.TypeOf(ByteSize(0)), "10MB")
+	if err != nil {
+		t.Fatalf("hook(string, ByteSize, \"10MB\") returned error: %v", err)
+	}
+	if got != 10*MB {
+		t.Fatalf("hook(string, ByteSize, \"10MB\") = %v, want %v", got, 10*MB)
+	}
+
+	// Non-ByteSize targets must be left untouched.
+	passthrough, err := hook(reflect.TypeOf(""), reflect.TypeOf(0), "10MB")
+	if err != nil {
+		t.Fatalf("hook(string, int, ...) returned error: %v", err)
+	}
+	if passthrough != "10MB" {
+		t.Fatalf("hook(string, int, ...) = %v, want unchanged input", passthrough)
+	}
+}
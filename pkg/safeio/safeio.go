@@ -8,6 +8,7 @@
 package safeio
 
 import (
+	"context"
 	"fmt"
 	"io"
 )
@@ -68,7 +69,21 @@ func (b ByteSize) String() string {
 // Because ReadAllLimit is defined to read from src until EOF it does not
 // treat an EOF from Read as an error to be reported. If the limit is reached
 // ReadAllLimit will return ErrLimitReached as an error.
+//
+// ReadAllLimit is a thin wrapper around ReadAllLimitContext using
+// context.Background(); callers that can be cancelled (e.g. a long-lived
+// watch or exporter stream) should call ReadAllLimitContext directly.
 func ReadAllLimit(r io.Reader, n ByteSize) ([]byte, error) {
+	return ReadAllLimitContext(context.Background(), r, n)
+}
+
+// ReadAllLimitContext behaves like ReadAllLimit but additionally honors ctx:
+// ctx is checked before each call to r.Read, and once it is done
+// ReadAllLimitContext returns the data read so far along with ctx.Err(). A
+// Read call that is already blocked is not interrupted by ctx. It is
+// intended for long-lived readers, such as the bodies of Kubernetes watch or
+// Hubble export streams, where the caller wants to stop between reads.
+func ReadAllLimitContext(ctx context.Context, r io.Reader, n ByteSize) ([]byte, error) {
 	// copied (with small modifications) from io.ReadAll
 	limit := int(n)
 	sz := 512
@@ -78,6 +93,12 @@ func ReadAllLimit(r io.Reader, n ByteSize) ([]byte, error) {
 	b := make([]byte, 0, sz)
 	var totalReadBytes int
 	for {
+		select {
+		case <-ctx.Done():
+			return b, ctx.Err()
+		default:
+		}
+
 		if len(b) == cap(b) {
 			// Add more capacity (let append pick how much).
 			b = append(b, 0)[:len(b)]
@@ -96,3 +117,64 @@ func ReadAllLimit(r io.Reader, n ByteSize) ([]byte, error) {
 		}
 	}
 }
+
+// limitedReader wraps an io.Reader and turns ErrLimitReached into a regular
+// read error once more than n bytes have been read cumulatively, mirroring
+// the size-tracking loop in ReadAllLimit but without buffering anything
+// itself.
+type limitedReader struct {
+	r         io.Reader
+	limit     int64
+	readSoFar int64
+}
+
+// NewLimitedReader returns an io.ReadCloser that reads from r but returns
+// ErrLimitReached from Read as soon as the cumulative byte count would
+// exceed n. Unlike ReadAllLimit/ReadAllLimitContext, it never buffers the
+// payload itself, so it is suitable for long-lived streams (e.g. Kubernetes
+// watch responses or Hubble exporter connections) that callers want to
+// stream straight through. Close closes the underlying reader if it
+// implements io.Closer.
+func NewLimitedReader(r io.Reader, n ByteSize) io.ReadCloser {
+	return &limitedReader{r: r, limit: int64(n)}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.readSoFar > l.limit {
+		return 0, l.limitReachedErr()
+	}
+
+	count, err := l.r.Read(p)
+	l.readSoFar += int64(count)
+	// Mirror ReadAllLimit's (err == nil || err == io.EOF) check: a reader is
+	// allowed to return the final chunk of data together with io.EOF in the
+	// same call, and that chunk can itself push us over the limit.
+	if (err == nil || err == io.EOF) && l.readSoFar > l.limit {
+		// Trim the bytes reported back to the caller to the remaining
+		// allowance, even though count bytes were already read from the
+		// underlying source into p, so callers streaming straight to a
+		// destination (e.g. CopyLimit) never write past n.
+		count -= int(l.readSoFar - l.limit)
+		return count, l.limitReachedErr()
+	}
+	return count, err
+}
+
+func (l *limitedReader) limitReachedErr() error {
+	return fmt.Errorf("%w: limit is %s", ErrLimitReached, ByteSize(l.limit))
+}
+
+func (l *limitedReader) Close() error {
+	if c, ok := l.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// CopyLimit copies from src to dst until an error, EOF, or after n bytes,
+// without allocating a buffer for the full payload (unlike ReadAllLimit).
+// It returns the number of bytes copied and, if the limit was reached,
+// ErrLimitReached.
+func CopyLimit(dst io.Writer, src io.Reader, n ByteSize) (int64, error) {
+	return io.Copy(dst, NewLimitedReader(src, n))
+}
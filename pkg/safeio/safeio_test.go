@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package safeio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// eofWithData returns (n, io.EOF) with data on its first and only Read
+// call, which io.Reader explicitly permits and which previously let
+// limitedReader bypass the limit entirely.
+type eofWithData struct {
+	data []byte
+	done bool
+}
+
+func (r *eofWithData) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	r.done = true
+	n := copy(p, r.data)
+	return n, io.EOF
+}
+
+func TestReadAllLimit(t *testing.T) {
+	data := []byte("hello world")
+
+	got, err := ReadAllLimit(bytes.NewReader(data), ByteSize(len(data)))
+	if err != nil {
+		t.Fatalf("ReadAllLimit with exact-fit limit returned error: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("ReadAllLimit = %q, want %q", got, data)
+	}
+
+	_, err = ReadAllLimit(bytes.NewReader(data), ByteSize(len(data)-1))
+	if !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("ReadAllLimit over limit returned %v, want ErrLimitReached", err)
+	}
+}
+
+func TestReadAllLimitContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ReadAllLimitContext(ctx, strings.NewReader("hello"), KB)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ReadAllLimitContext with cancelled context returned %v, want context.Canceled", err)
+	}
+}
+
+func TestLimitedReaderCapsOutput(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 4096)
+	lr := NewLimitedReader(bytes.NewReader(data), ByteSize(10))
+	defer lr.Close()
+
+	var got []byte
+	buf := make([]byte, 1024)
+	var readErr error
+	for {
+		n, err := lr.Read(buf)
+		got = append(got, buf[:n]...)
+		if err != nil {
+			readErr = err
+			break
+		}
+	}
+
+	if !errors.Is(readErr, ErrLimitReached) {
+		t.Fatalf("Read returned %v, want ErrLimitReached", readErr)
+	}
+	if len(got) != 10 {
+		t.Fatalf("LimitedReader handed back %d bytes, want exactly 10 (the limit)", len(got))
+	}
+}
+
+func TestLimitedReaderExactFitIsNotAnError(t *testing.T) {
+	data := []byte("0123456789")
+	lr := NewLimitedReader(bytes.NewReader(data), ByteSize(len(data)))
+	defer lr.Close()
+
+	got, err := io.ReadAll(lr)
+	if err != nil {
+		t.Fatalf("reading exactly n bytes returned error: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("LimitedReader = %q, want %q", got, data)
+	}
+}
+
+func TestLimitedReaderEOFAtLimitIsFlagged(t *testing.T) {
+	data := bytes.Repeat([]byte("y"), 20)
+	lr := NewLimitedReader(&eofWithData{data: data}, ByteSize(10))
+	defer lr.Close()
+
+	buf := make([]byte, 20)
+	n, err := lr.Read(buf)
+	if !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("Read of an (data, io.EOF) reader over the limit returned %v, want ErrLimitReached", err)
+	}
+	if n != 10 {
+		t.Fatalf("Read returned n=%d, want 10 (capped to the limit)", n)
+	}
+}
+
+func TestCopyLimit(t *testing.T) {
+	data := bytes.Repeat([]byte("z"), 100)
+	var dst bytes.Buffer
+
+	n, err := CopyLimit(&dst, bytes.NewReader(data), ByteSize(10))
+	if !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("CopyLimit returned %v, want ErrLimitReached", err)
+	}
+	if n != 10 {
+		t.Fatalf("CopyLimit copied %d bytes, want 10", n)
+	}
+	if dst.Len() != 10 {
+		t.Fatalf("CopyLimit wrote %d bytes to dst, want exactly 10 (the limit), not more", dst.Len())
+	}
+}
+
+func TestLimitedReaderCloseClosesUnderlying(t *testing.T) {
+	rc := &closeTrackingReader{Reader: strings.NewReader("hello")}
+	lr := NewLimitedReader(rc, KB)
+
+	if err := lr.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if !rc.closed {
+		t.Fatal("Close did not close the underlying io.Closer")
+	}
+}
+
+type closeTrackingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTrackingReader) Close() error {
+	c.closed = true
+	return nil
+}